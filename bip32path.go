@@ -0,0 +1,111 @@
+package main
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// defaultPath is core's historical default: a 3-level, fully hardened path,
+// as opposed to the 5-level BIP44 m/44'/coin'/account'/change/index layout.
+const defaultPath = "m/0'/0'/k'"
+
+const hardened = hdkeychain.HardenedKeyStart
+
+// pathComponent is one level of a parsed derivation path. isIndex marks the
+// "k" placeholder, which decode39 substitutes with the per-address index.
+type pathComponent struct {
+	index    uint32
+	hardened bool
+	isIndex  bool
+}
+
+// BIP44Params is a parsed BIP32/BIP44 derivation path, e.g.
+// m/44'/0'/0'/0/k, as a sequence of components in order. deriveChild walks
+// Components directly, so this works for arbitrary-depth paths, not just
+// the standard 5-level BIP44 layout.
+type BIP44Params struct {
+	Components []pathComponent
+}
+
+// parsePath parses a path string like "m/44'/0'/0'/0/k" into a BIP44Params.
+// Hardened components must be marked with a trailing ', and exactly one
+// component must be the literal "k" (optionally hardened, e.g. "k'"),
+// standing in for the per-address index that decode39 fills in on each
+// derivation. Non-hardened indices must stay below 2^31
+// (hdkeychain.HardenedKeyStart); use ' to harden instead.
+func parsePath(path string) (BIP44Params, error) {
+	if path == "" {
+		path = defaultPath
+	}
+
+	parts := strings.Split(path, "/")
+	if len(parts) < 2 || parts[0] != "m" {
+		return BIP44Params{}, fmt.Errorf("path %q must start with \"m/\"", path)
+	}
+	parts = parts[1:]
+
+	var params BIP44Params
+	sawIndex := false
+
+	for _, p := range parts {
+		hardened := strings.HasSuffix(p, "'")
+		if hardened {
+			p = p[:len(p)-1]
+		}
+
+		if p == "k" {
+			if sawIndex {
+				return BIP44Params{}, fmt.Errorf("path %q: \"k\" placeholder given more than once", path)
+			}
+			sawIndex = true
+			params.Components = append(params.Components, pathComponent{isIndex: true, hardened: hardened})
+			continue
+		}
+
+		n, err := strconv.ParseUint(p, 10, 32)
+		if err != nil {
+			return BIP44Params{}, fmt.Errorf("path %q: bad component %q: %v", path, p, err)
+		}
+		if !hardened && n >= hdkeychain.HardenedKeyStart {
+			return BIP44Params{}, fmt.Errorf("path %q: non-hardened index %d must stay below 2^31", path, n)
+		}
+
+		params.Components = append(params.Components, pathComponent{index: uint32(n), hardened: hardened})
+	}
+
+	if !sawIndex {
+		return BIP44Params{}, fmt.Errorf("path %q must contain a \"k\" component for the per-address index", path)
+	}
+
+	return params, nil
+}
+
+// deriveChild walks master down params, substituting k for the path's "k"
+// placeholder component.
+func deriveChild(master *hdkeychain.ExtendedKey, params BIP44Params, k int64) (*hdkeychain.ExtendedKey, error) {
+	if k < 0 || k >= hardened {
+		return nil, fmt.Errorf("address index %d must stay below 2^31", k)
+	}
+
+	key := master
+	for _, c := range params.Components {
+		index := c.index
+		if c.isIndex {
+			index = uint32(k)
+		}
+		if c.hardened {
+			index += hardened
+		}
+
+		next, err := key.Child(index)
+		if err != nil {
+			return nil, err
+		}
+		key = next
+	}
+
+	return key, nil
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/btcsuite/btcd/chaincfg"
+	"github.com/btcsuite/btcutil/hdkeychain"
+)
+
+// TestParsePathDefault pins the derivation path used when -path isn't
+// given: core's historical m/0'/0'/k', fully hardened. An earlier version
+// of this code dropped the hardened bit on the index component, which
+// would have silently derived different keys/addresses for the same
+// mnemonic than every release before it.
+func TestParsePathDefault(t *testing.T) {
+	params, err := parsePath("")
+	if err != nil {
+		t.Fatalf(`parsePath(""): %v`, err)
+	}
+
+	if len(params.Components) != 3 {
+		t.Fatalf("default path: want 3 components, got %d", len(params.Components))
+	}
+	for i, c := range params.Components {
+		if !c.hardened {
+			t.Errorf("default path component %d: want hardened, got non-hardened", i)
+		}
+	}
+	if !params.Components[2].isIndex {
+		t.Errorf("default path: last component should be the \"k\" placeholder")
+	}
+}
+
+// TestParsePathHardenedIndex confirms "m/0'/0'/k'" -- the only spelling
+// that reproduces the old hardcoded default via -path -- parses, with the
+// index placeholder marked hardened.
+func TestParsePathHardenedIndex(t *testing.T) {
+	params, err := parsePath("m/0'/0'/k'")
+	if err != nil {
+		t.Fatalf("parsePath(%q): %v", "m/0'/0'/k'", err)
+	}
+
+	last := params.Components[len(params.Components)-1]
+	if !last.isIndex || !last.hardened {
+		t.Errorf("want a hardened index placeholder, got %+v", last)
+	}
+}
+
+// TestDeriveChildDefaultPath pins deriveChild's output for the default
+// path against deriving m/0'/0'/0' by hand, so a regression in how the
+// "k" placeholder's hardened bit is applied gets caught.
+func TestDeriveChildDefaultPath(t *testing.T) {
+	seed := make([]byte, 32)
+	for i := range seed {
+		seed[i] = byte(i)
+	}
+
+	master, err := hdkeychain.NewMaster(seed, &chaincfg.MainNetParams)
+	if err != nil {
+		t.Fatalf("NewMaster: %v", err)
+	}
+
+	params, err := parsePath(defaultPath)
+	if err != nil {
+		t.Fatalf("parsePath(defaultPath): %v", err)
+	}
+
+	got, err := deriveChild(master, params, 0)
+	if err != nil {
+		t.Fatalf("deriveChild: %v", err)
+	}
+
+	account, err := master.Child(hardened)
+	if err != nil {
+		t.Fatalf("account.Child: %v", err)
+	}
+	chain, err := account.Child(hardened)
+	if err != nil {
+		t.Fatalf("chain.Child: %v", err)
+	}
+	want, err := chain.Child(hardened)
+	if err != nil {
+		t.Fatalf("index.Child: %v", err)
+	}
+
+	if got.String() != want.String() {
+		t.Errorf("deriveChild(defaultPath, 0) = %s, want %s (m/0'/0'/0')", got.String(), want.String())
+	}
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/btcsuite/btcutil"
+	"github.com/btcsuite/btcutil/hdkeychain"
+	"github.com/tyler-smith/go-bip39"
+)
+
+// new39 generates a fresh BIP39 mnemonic of the requested bit length and
+// writes it out.
+func (g *GDsession) new39() error {
+	entropy, err := bip39.NewEntropy(int(*g.bits))
+	if err != nil {
+		return err
+	}
+	mnemonic, err := bip39.NewMnemonic(entropy)
+	if err != nil {
+		return err
+	}
+	return g.output(mnemonic)
+}
+
+// mnemonicPassphrase returns the BIP39 passphrase ("25th word") to mix into
+// seed derivation, taking it from -bip39pass or prompting for it when not
+// given. The passphrase is optional; an empty answer is fine.
+func (g *GDsession) mnemonicPassphrase() (string, error) {
+	if *g.bip39pass != "" {
+		return *g.bip39pass, nil
+	}
+	pw, err := g.prompt("bip39 passphrase (25th word, enter for none): ")
+	if err != nil {
+		return "", err
+	}
+	return string(pw), nil
+}
+
+// decode39 parses a BIP39 mnemonic phrase (read as input text) and writes
+// out the derived addresses, or WIF keys and addresses when wif is true.
+func (g *GDsession) decode39(wif bool) error {
+	mnemonic, err := g.inputText()
+	if err != nil {
+		return err
+	}
+	if !bip39.IsMnemonicValid(mnemonic) {
+		return fmt.Errorf("invalid mnemonic phrase")
+	}
+
+	passphrase, err := g.mnemonicPassphrase()
+	if err != nil {
+		return err
+	}
+
+	master, err := hdkeychain.NewMaster(bip39.NewSeed(mnemonic, passphrase), g.NetParams)
+	if err != nil {
+		return err
+	}
+
+	params, err := parsePath(*g.path)
+	if err != nil {
+		return err
+	}
+
+	var lines []string
+	for k := int64(0); k < *g.index; k++ {
+		child, err := deriveChild(master, params, k)
+		if err != nil {
+			return err
+		}
+
+		adr, err := child.Address(g.NetParams)
+		if err != nil {
+			return err
+		}
+
+		if !wif {
+			lines = append(lines, adr.String())
+			continue
+		}
+
+		priv, err := child.ECPrivKey()
+		if err != nil {
+			return err
+		}
+		wifKey, err := btcutil.NewWIF(priv, g.NetParams, true)
+		if err != nil {
+			return err
+		}
+		lines = append(lines, fmt.Sprintf("%s %s", wifKey.String(), adr.String()))
+	}
+
+	return g.output(strings.Join(lines, "\n"))
+}
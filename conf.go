@@ -0,0 +1,110 @@
+package main
+
+import (
+	"fmt"
+
+	"github.com/Varunram/goodelivery/config"
+)
+
+// loadConfFile unlocks -conf under a master password prompted via
+// g.prompt(), and fills in -wif/-dest from it when those weren't already
+// given on the command line.
+func (g *GDsession) loadConfFile() error {
+	cf, err := config.Load(*g.confFileName)
+	if err != nil {
+		return err
+	}
+
+	password, err := g.prompt("conf file master password: ")
+	if err != nil {
+		return err
+	}
+
+	secrets, err := cf.Open(password)
+	if err != nil {
+		return err
+	}
+
+	g.confWIF = secrets.WIF
+	g.confDestAdr = secrets.DestAdr
+
+	if *g.wifkey == "" {
+		*g.wifkey = g.confWIF
+	}
+	if *g.destAdr == "" {
+		*g.destAdr = g.confDestAdr
+	}
+
+	return nil
+}
+
+// confInit creates a fresh -conf file, wrapping the default WIF/dest
+// secrets (taken from -wif/-dest if given, otherwise prompted for) under a
+// newly chosen master password and a freshly generated scrypt salt.
+func (g *GDsession) confInit() error {
+	if *g.confFileName == "" {
+		return fmt.Errorf("conf-init needs -conf <path>")
+	}
+
+	wif := *g.wifkey
+	if wif == "" {
+		b, err := g.prompt("default WIF key (enter for none): ")
+		if err != nil {
+			return err
+		}
+		wif = string(b)
+	}
+
+	dest := *g.destAdr
+	if dest == "" {
+		b, err := g.prompt("default destination address (enter for none): ")
+		if err != nil {
+			return err
+		}
+		dest = string(b)
+	}
+
+	password, err := g.prompt("new conf file master password: ")
+	if err != nil {
+		return err
+	}
+
+	cf, err := config.New(password, config.Secrets{
+		WIF:     wif,
+		DestAdr: dest,
+	})
+	if err != nil {
+		return err
+	}
+
+	return cf.Save(*g.confFileName)
+}
+
+// confChpass re-wraps an existing -conf file's secrets under a new master
+// password, without touching the secrets themselves.
+func (g *GDsession) confChpass() error {
+	if *g.confFileName == "" {
+		return fmt.Errorf("conf-chpass needs -conf <path>")
+	}
+
+	cf, err := config.Load(*g.confFileName)
+	if err != nil {
+		return err
+	}
+
+	oldPassword, err := g.prompt("current conf file master password: ")
+	if err != nil {
+		return err
+	}
+	newPassword, err := g.prompt("new conf file master password: ")
+	if err != nil {
+		return err
+	}
+
+	rewrapped, err := cf.Rewrap(oldPassword, newPassword)
+	if err != nil {
+		return err
+	}
+
+	return rewrapped.Save(*g.confFileName)
+}
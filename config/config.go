@@ -0,0 +1,166 @@
+// Package config implements goodelivery's encrypted session config file: a
+// JSON document holding a scrypt KDF descriptor and an AES-GCM-encrypted
+// blob of the secrets a repeat offline operator would otherwise have to
+// re-type (-conf in the main package loads one of these).
+package config
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"golang.org/x/crypto/scrypt"
+)
+
+const (
+	scryptN = 1 << 18
+	scryptR = 8
+	scryptP = 1
+
+	saltLen = 32
+	keyLen  = 32 // AES-256
+)
+
+// KDF is the scrypt cost parameters and salt used to stretch a master
+// password into the AES-GCM key that wraps a ConfFile's Secrets.
+type KDF struct {
+	N    int    `json:"n"`
+	R    int    `json:"r"`
+	P    int    `json:"p"`
+	Salt []byte `json:"salt"`
+}
+
+// Secrets is the sensitive material a ConfFile carries, so move/insert can
+// run without re-entering it each invocation.
+type Secrets struct {
+	WIF     string `json:"wif"`
+	DestAdr string `json:"destAdr"`
+}
+
+// ConfFile is the on-disk, password-protected session config: a KDF
+// descriptor plus the AES-GCM nonce and ciphertext wrapping a Secrets.
+type ConfFile struct {
+	KDF        KDF    `json:"kdf"`
+	Nonce      []byte `json:"nonce"`
+	Ciphertext []byte `json:"ciphertext"`
+}
+
+// deriveKey stretches password into an AES-256 key using k's parameters.
+func (k KDF) deriveKey(password []byte) ([]byte, error) {
+	return scrypt.Key(password, k.Salt, k.N, k.R, k.P, keyLen)
+}
+
+// newKDF generates a fresh KDF descriptor: a random salt under this
+// subsystem's standard scrypt cost.
+func newKDF() (KDF, error) {
+	salt := make([]byte, saltLen)
+	if _, err := rand.Read(salt); err != nil {
+		return KDF{}, err
+	}
+	return KDF{N: scryptN, R: scryptR, P: scryptP, Salt: salt}, nil
+}
+
+// New encrypts secrets under password behind a freshly generated KDF salt.
+func New(password []byte, secrets Secrets) (*ConfFile, error) {
+	kdf, err := newKDF()
+	if err != nil {
+		return nil, err
+	}
+	return seal(kdf, password, secrets)
+}
+
+// seal encrypts secrets under password using kdf's parameters and salt.
+func seal(kdf KDF, password []byte, secrets Secrets) (*ConfFile, error) {
+	key, err := kdf.deriveKey(password)
+	if err != nil {
+		return nil, err
+	}
+
+	plain, err := json.Marshal(secrets)
+	if err != nil {
+		return nil, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+
+	return &ConfFile{
+		KDF:        kdf,
+		Nonce:      nonce,
+		Ciphertext: gcm.Seal(nil, nonce, plain, nil),
+	}, nil
+}
+
+func newGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// Load reads and parses a ConfFile from path.
+func Load(path string) (*ConfFile, error) {
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var cf ConfFile
+	if err := json.Unmarshal(b, &cf); err != nil {
+		return nil, err
+	}
+	return &cf, nil
+}
+
+// Save writes cf to path as JSON.
+func (cf *ConfFile) Save(path string) error {
+	b, err := json.MarshalIndent(cf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return ioutil.WriteFile(path, b, 0600)
+}
+
+// Open decrypts cf's Secrets under password.
+func (cf *ConfFile) Open(password []byte) (Secrets, error) {
+	key, err := cf.KDF.deriveKey(password)
+	if err != nil {
+		return Secrets{}, err
+	}
+
+	gcm, err := newGCM(key)
+	if err != nil {
+		return Secrets{}, err
+	}
+
+	plain, err := gcm.Open(nil, cf.Nonce, cf.Ciphertext, nil)
+	if err != nil {
+		return Secrets{}, fmt.Errorf("wrong password, or corrupt conf file: %v", err)
+	}
+
+	var secrets Secrets
+	if err := json.Unmarshal(plain, &secrets); err != nil {
+		return Secrets{}, err
+	}
+	return secrets, nil
+}
+
+// Rewrap re-encrypts cf's Secrets under a new password and a fresh KDF
+// salt, without changing the underlying secrets.
+func (cf *ConfFile) Rewrap(oldPassword, newPassword []byte) (*ConfFile, error) {
+	secrets, err := cf.Open(oldPassword)
+	if err != nil {
+		return nil, err
+	}
+	return New(newPassword, secrets)
+}
@@ -1,7 +1,6 @@
 package main
 
 import (
-	"bufio"
 	"encoding/hex"
 	"flag"
 	"fmt"
@@ -10,8 +9,7 @@ import (
 	"os"
 	"strings"
 
-	"github.com/adiabat/btcd/chaincfg"
-	"github.com/howeyc/gopass"
+	"github.com/btcsuite/btcd/chaincfg"
 )
 
 /* goodelivery --
@@ -54,11 +52,26 @@ commands:
 	as this is an offline tool, the transaction is saved to disk and can be
 	exported / printed
 
+	verify /
+	check a -signkey signed output file against an ed25519 public key
+	(-signkey on move/extract wraps output() in a signify-style header so
+	an air-gapped signer and an online broadcaster can trust a hand-carried
+	file without GPG)
+
+[conf file]
+	conf-init /
+	create a -conf file: a scrypt+AES-GCM encrypted blob holding a default
+	WIF and destination address behind a master password, so repeat
+	offline use doesn't mean re-entering them every invocation
+
+	conf-chpass /
+	re-wrap an existing -conf file's secrets under a new master password
+
 */
 
 func usage() {
 	fmt.Printf("Usage:\n./goodelivery command -options\n")
-	fmt.Printf("commands: new adr key dec enc extract insert move\n")
+	fmt.Printf("commands: new adr key dec enc extract insert move verify conf-init conf-chpass\n")
 	//	fmt.Printf("or ./goodelivery BIP38 privkey\n")
 }
 
@@ -78,10 +91,22 @@ type GDsession struct {
 	// bip38 can be supplied from command line, or read from a file
 	bip38key *string // bip38 string from command line
 
+	signKeyFile *string // ed25519 secret key file to sign output() with
+	verKeyFile  *string // ed25519 public key file for the verify command
+
+	confFileName *string // scrypt-encrypted session config file (-conf)
+
+	// decrypted from confFileName by LoadFiles, so move/insert can run
+	// without re-entering them
+	confWIF     string
+	confDestAdr string
+
 	destAdr *string // destination address to send to
 
 	pass *string // password from cli args, bypassing entry (risky)
 
+	bip39pass *string // bip39 passphrase ("25th word"), bypassing entry
+
 	bits  *int64 // bitlength of bip39 seed
 	index *int64 // index for selecting txos from txs
 	fee   *int64 // fee in satoshis per byte
@@ -90,8 +115,10 @@ type GDsession struct {
 	star    *bool // echo ****s to screen? default false
 	verbose *bool // say more stuff. default false
 
-	bip44   *bool // bip44 derivation paths (defaults to core's m/0'/0'/k')
-	mainArg *bool // flag to set mainnet
+	maxInputLen *int64 // max bytes prompt() will accept in one go
+
+	path    *string // bip32/bip44 derivation path (defaults to core's m/0'/0'/k')
+	mainArg *bool   // flag to set mainnet
 
 	// defaults to testnet, not mainnet.  not reccommended for mainnet yet.
 	NetParams *chaincfg.Params
@@ -109,11 +136,22 @@ func (g *GDsession) setFlags(fset *flag.FlagSet) {
 
 	g.bip38key = fset.String("b38", "", "bip38 encrypted private key")
 
+	g.signKeyFile = fset.String("signkey", "",
+		"ed25519 secret key file to sign output() with")
+	g.verKeyFile = fset.String("verkey", "",
+		"ed25519 public key file, for the verify command")
+
+	g.confFileName = fset.String("conf", "",
+		"scrypt-encrypted session config file holding default secrets")
+
 	g.destAdr = fset.String("dest", "", "destination bitcoin address")
 
 	g.pass = fset.String("pass", "",
 		"passphrase / salt given on command line (unsafe!)")
 
+	g.bip39pass = fset.String("bip39pass", "",
+		"bip39 passphrase / 25th word, given on command line (unsafe!)")
+
 	g.bits = fset.Int64("b", 128, "bit length of mnemonic seed")
 	g.index = fset.Int64("n", 21, "number (txo index, num of adrs)")
 
@@ -123,8 +161,12 @@ func (g *GDsession) setFlags(fset *flag.FlagSet) {
 	g.star = fset.Bool("star", false, "echo text entry as ****")
 	g.verbose = fset.Bool("v", false, "verbose mode")
 
+	g.maxInputLen = fset.Int64("maxlen", 8192,
+		"max bytes of sensitive input prompt() will accept in one go")
+
 	g.mainArg = fset.Bool("main", true, "use mainnet (not testnet3)")
-	g.bip44 = fset.Bool("b44", false, "use bip44 key derivation (default m/0'/0'/k')")
+	g.path = fset.String("path", "",
+		"derivation path, e.g. m/44'/0'/0'/0/k (default m/0'/0'/k')")
 
 }
 
@@ -134,20 +176,24 @@ func (g *GDsession) prompt(pr string) ([]byte, error) {
 		return []byte(*g.pass), nil
 	}
 	fmt.Printf(pr)
+
 	// star gets priority; people might set echo and star on by accident
-	if *g.star {
-		return gopass.GetPasswdMasked()
-	}
-	if *g.echo {
-		reader := bufio.NewReaderSize(os.Stdin, 32767)
-		rawread, err := reader.ReadString('\n') // input finishes on enter key
-		rawread = rawread[:len(rawread)-1]      // strip enter from end of read
-		return []byte(rawread), err
+	mode := echoHidden
+	switch {
+	case *g.star:
+		mode = echoStar
+	case *g.echo:
+		mode = echoClear
 	}
-	return gopass.GetPasswd()
+
+	return g.readSecure(mode)
 }
 
 func (g *GDsession) output(s string) error {
+	s, err := g.signOutput(s)
+	if err != nil {
+		return err
+	}
 	s += fmt.Sprintf("\n")
 	if *g.outFileName != "" {
 		return ioutil.WriteFile(*g.outFileName, []byte(s), 0600)
@@ -201,6 +247,14 @@ func (g *GDsession) LoadFiles() error {
 		g.inFile = string(b)
 	}
 
+	// conf-init/conf-chpass operate on the conf file itself, so they must
+	// not try to unlock it first
+	if *g.confFileName != "" && g.command != "conf-init" && g.command != "conf-chpass" {
+		if err := g.loadConfFile(); err != nil {
+			return err
+		}
+	}
+
 	return nil
 }
 
@@ -223,6 +277,12 @@ func (g *GDsession) Run() error {
 		err = g.insert()
 	case "move":
 		err = g.move()
+	case "verify":
+		err = g.verify()
+	case "conf-init":
+		err = g.confInit()
+	case "conf-chpass":
+		err = g.confChpass()
 	default:
 		usage()
 	}
@@ -0,0 +1,156 @@
+package main
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"golang.org/x/term"
+)
+
+// bracketed-paste markers some terminals wrap pasted text in. gopass (and a
+// plain canonical-mode read) don't know about these, so a pasted 24-word
+// mnemonic or BIP38 string ends up with these escape bytes baked into it.
+const (
+	pasteStart = "\x1b[200~"
+	pasteEnd   = "\x1b[201~"
+)
+
+const (
+	backspace = 0x08
+	del       = 0x7f
+)
+
+// echoMode controls what readSecure shows on screen as the user types.
+type echoMode int
+
+const (
+	echoHidden echoMode = iota // no feedback at all
+	echoStar                   // one '*' per character
+	echoClear                  // the character itself, in the clear
+)
+
+// readSecure reads one line of sensitive input. -star and the default
+// hidden mode put the terminal in raw mode so they can mask keystrokes and
+// strip bracketed-paste markers; -echo needs neither, and raw mode would
+// break it on piped/non-tty stdin (scripted input), so it falls back to a
+// plain, tty-independent read instead, same as before this reader existed.
+func (g *GDsession) readSecure(mode echoMode) ([]byte, error) {
+	if mode == echoClear {
+		line, err := readPlainLine(bufio.NewReader(os.Stdin))
+		if err == nil && int64(len(line)) > *g.maxInputLen {
+			return nil, fmt.Errorf("input exceeds max length of %d bytes", *g.maxInputLen)
+		}
+		return line, err
+	}
+
+	fd := int(os.Stdin.Fd())
+	oldState, err := term.MakeRaw(fd)
+	if err != nil {
+		return nil, err
+	}
+	defer term.Restore(fd, oldState)
+
+	line, err := readMaskedLine(bufio.NewReader(os.Stdin), mode, int(*g.maxInputLen))
+	fmt.Println()
+	return line, err
+}
+
+// readPlainLine reads a line the way the original gopass-era -echo branch
+// did: canonical terminal handling, no masking, no paste stripping.
+func readPlainLine(r *bufio.Reader) ([]byte, error) {
+	line, err := r.ReadString('\n')
+	if err != nil {
+		return nil, err
+	}
+	return []byte(strings.TrimRight(line, "\r\n")), nil
+}
+
+// readMaskedLine reads bytes from r up to a line terminator, echoing per
+// mode, dropping any bracketed-paste marker bytes along the way, and
+// honoring backspace/delete so a mistyped mnemonic or WIF can be corrected
+// instead of baking a stray control byte into the secret.
+func readMaskedLine(r *bufio.Reader, mode echoMode, maxLen int) ([]byte, error) {
+	var buf, pending []byte
+
+	for {
+		b, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+
+		if b == '\r' {
+			// normalize CRLF -> LF: swallow a following \n, if there is one
+			// already buffered (don't block waiting for another key)
+			if r.Buffered() > 0 {
+				if next, err := r.ReadByte(); err == nil && next != '\n' {
+					r.UnreadByte()
+				}
+			}
+			break
+		}
+		if b == '\n' {
+			break
+		}
+
+		if b == backspace || b == del {
+			// mid-escape-sequence: no character was committed yet, just
+			// drop what we'd buffered of it
+			if len(pending) > 0 {
+				pending = nil
+				continue
+			}
+			if len(buf) > 0 {
+				buf = buf[:len(buf)-1]
+				unechoByte(mode)
+			}
+			continue
+		}
+
+		pending = append(pending, b)
+		if pendingIsMarkerPrefix(pending) {
+			if string(pending) == pasteStart || string(pending) == pasteEnd {
+				pending = nil
+			}
+			continue
+		}
+
+		for _, c := range pending {
+			if len(buf) >= maxLen {
+				return nil, fmt.Errorf("input exceeds max length of %d bytes", maxLen)
+			}
+			buf = append(buf, c)
+			echoByte(mode, c)
+		}
+		pending = nil
+	}
+
+	return buf, nil
+}
+
+// pendingIsMarkerPrefix reports whether pending could still grow into one
+// of the bracketed-paste markers.
+func pendingIsMarkerPrefix(pending []byte) bool {
+	s := string(pending)
+	return strings.HasPrefix(pasteStart, s) || strings.HasPrefix(pasteEnd, s)
+}
+
+func echoByte(mode echoMode, b byte) {
+	switch mode {
+	case echoStar:
+		fmt.Print("*")
+	case echoClear:
+		os.Stdout.Write([]byte{b})
+	}
+}
+
+// unechoByte visually erases the feedback echoByte printed for one
+// character, for modes that printed any. echoClear never reaches
+// readMaskedLine (readSecure routes it to readPlainLine instead), so only
+// echoStar needs erasing here.
+func unechoByte(mode echoMode) {
+	if mode == echoStar {
+		fmt.Print("\b \b")
+	}
+}